@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const sqlMigrationTemplate = `-- +goose Up
+
+-- +goose Down
+`
+
+const goMigrationTemplate = `package migrations
+
+import (
+	"database/sql"
+
+	"github.com/huynguyenh/goose"
+)
+
+func init() {
+	goose.AddMigration("%s", up%s, down%s)
+}
+
+func up%s(tx *sql.Tx) error {
+	return nil
+}
+
+func down%s(tx *sql.Tx) error {
+	return nil
+}
+`
+
+// runCreate scaffolds a new migration file in the migrations directory,
+// named "<UTC-timestamp>_<name>.<ext>" so parallel branches never collide
+// on a version number the way the old monotonic-integer scheme could.
+func runCreate(conf *DBConf, name, migrationType string) {
+
+	migrationsDir := path.Join(*dbFolder, "migrations")
+	timestamp := time.Now().UTC().Format("20060102150405")
+	filename := fmt.Sprintf("%s_%s.%s", timestamp, name, migrationType)
+	fullpath := path.Join(migrationsDir, filename)
+
+	var contents string
+	switch migrationType {
+	case "sql":
+		contents = sqlMigrationTemplate
+	case "go":
+		funcName := goIdentifier(name)
+		contents = fmt.Sprintf(goMigrationTemplate, filename, funcName, funcName, funcName, funcName)
+	default:
+		log.Fatalf("migration type must be 'sql' or 'go', got %q", migrationType)
+	}
+
+	if _, err := os.Stat(fullpath); err == nil {
+		log.Fatalf("goose: %s already exists, refusing to overwrite it", fullpath)
+	} else if !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(fullpath, []byte(contents), 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("goose: created", fullpath)
+}
+
+// goIdentifier turns an arbitrary migration name (which may contain
+// spaces, hyphens, or other characters illegal in a Go identifier, e.g.
+// "add-users" or "add users") into a valid, exported Go identifier by
+// title-casing each run of letters/digits and dropping everything else.
+func goIdentifier(name string) string {
+	var b strings.Builder
+	capNext := true
+
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if capNext {
+				b.WriteRune(unicode.ToUpper(r))
+				capNext = false
+			} else {
+				b.WriteRune(r)
+			}
+		default:
+			capNext = true
+		}
+	}
+
+	ident := b.String()
+	if ident == "" {
+		return "Migration"
+	}
+	if unicode.IsDigit(rune(ident[0])) {
+		ident = "M" + ident
+	}
+	return ident
+}
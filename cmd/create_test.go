@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestGoIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"add_users":    "AddUsers",
+		"add-users":    "AddUsers",
+		"add users":    "AddUsers",
+		"AddUsers":     "AddUsers",
+		"2fa_tokens":   "M2faTokens",
+		"":             "Migration",
+		"---":          "Migration",
+	}
+
+	for name, want := range cases {
+		if got := goIdentifier(name); got != want {
+			t.Errorf("goIdentifier(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"database/sql"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+
+	"github.com/huynguyenh/goose"
+)
+
+// openMigrationDB opens the configured DB, resolves its SqlDialect, and
+// returns an fs.FS over its migrations directory — the setup every CLI
+// command (migrate, status, redo, reset) needs before calling into the
+// goose library.
+func openMigrationDB(conf *DBConf) (*sql.DB, goose.SqlDialect, fs.FS) {
+
+	db, err := sql.Open(conf.Driver, conf.OpenStr)
+	if err != nil {
+		log.Fatal("couldn't open DB:", err)
+	}
+
+	dialect, err := goose.GetDialect(conf.Driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	migrationsDir := path.Join(*dbFolder, "migrations")
+
+	return db, dialect, os.DirFS(migrationsDir)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log"
+
+	"github.com/huynguyenh/goose"
+)
+
+// runRedo is a thin wrapper around goose.Redo.
+func runRedo(conf *DBConf) {
+
+	db, dialect, fsys := openMigrationDB(conf)
+
+	if err := goose.Redo(db, fsys, dialect); err != nil {
+		log.Fatalf("FAIL %v, quitting redo", err)
+	}
+}
+
+// runReset is a thin wrapper around goose.Reset.
+func runReset(conf *DBConf) {
+
+	db, dialect, fsys := openMigrationDB(conf)
+
+	if err := goose.Reset(db, fsys, dialect); err != nil {
+		log.Fatalf("FAIL %v, quitting reset", err)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"log"
+
+	"github.com/huynguyenh/goose"
+)
+
+// runStatus is a thin wrapper around goose.Status.
+func runStatus(conf *DBConf) {
+
+	db, dialect, fsys := openMigrationDB(conf)
+
+	if err := goose.Status(db, fsys, dialect); err != nil {
+		log.Fatal(err)
+	}
+}
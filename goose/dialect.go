@@ -0,0 +1,114 @@
+package goose
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SqlDialect abstracts the handful of statements that differ between the
+// database engines goose supports, so the rest of the library can work
+// against goose_db_version without caring which driver is in use.
+type SqlDialect interface {
+	CreateVersionTableSQL() string // sql string to create the goose_db_version table
+	InsertVersionSQL() string      // sql string to insert a new version into goose_db_version
+	DbVersionQuery(db *sql.DB) (*sql.Rows, error)
+	StatusQuery(db *sql.DB) (*sql.Rows, error) // full goose_db_version log, for the status command
+}
+
+// GetDialect returns the SqlDialect registered for the given driver name
+// ("postgres", "mysql" or "sqlite3").
+func GetDialect(driver string) (SqlDialect, error) {
+	switch driver {
+	case "postgres":
+		return &PostgresDialect{}, nil
+	case "mysql":
+		return &MySqlDialect{}, nil
+	case "sqlite3":
+		return &Sqlite3Dialect{}, nil
+	default:
+		return nil, fmt.Errorf("no dialect registered for driver %q", driver)
+	}
+}
+
+////////////////
+// Postgres
+////////////////
+
+type PostgresDialect struct{}
+
+func (pg PostgresDialect) CreateVersionTableSQL() string {
+	return `CREATE TABLE goose_db_version (
+                id serial NOT NULL,
+                version_id int NOT NULL,
+                is_applied boolean NOT NULL,
+                tstamp timestamp NULL default now(),
+                PRIMARY KEY(id)
+              );`
+}
+
+func (pg PostgresDialect) InsertVersionSQL() string {
+	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2);"
+}
+
+func (pg PostgresDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC;")
+}
+
+func (pg PostgresDialect) StatusQuery(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT version_id, is_applied, tstamp from goose_db_version ORDER BY id DESC;")
+}
+
+////////////////
+// MySQL
+////////////////
+
+type MySqlDialect struct{}
+
+func (m MySqlDialect) CreateVersionTableSQL() string {
+	return `CREATE TABLE goose_db_version (
+                id serial NOT NULL,
+                version_id int NOT NULL,
+                is_applied boolean NOT NULL,
+                tstamp timestamp NULL default CURRENT_TIMESTAMP,
+                PRIMARY KEY(id)
+              );`
+}
+
+func (m MySqlDialect) InsertVersionSQL() string {
+	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?);"
+}
+
+func (m MySqlDialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC;")
+}
+
+func (m MySqlDialect) StatusQuery(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT version_id, is_applied, tstamp from goose_db_version ORDER BY id DESC;")
+}
+
+////////////////
+// Sqlite3
+////////////////
+
+type Sqlite3Dialect struct{}
+
+func (s Sqlite3Dialect) CreateVersionTableSQL() string {
+	return `CREATE TABLE goose_db_version (
+                id integer NOT NULL primary key autoincrement,
+                version_id int NOT NULL,
+                is_applied boolean NOT NULL,
+                tstamp timestamp NULL default (datetime('now'))
+              );`
+}
+
+func (s Sqlite3Dialect) InsertVersionSQL() string {
+	return "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?);"
+}
+
+func (s Sqlite3Dialect) DbVersionQuery(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT version_id, is_applied from goose_db_version ORDER BY id DESC;")
+}
+
+func (s Sqlite3Dialect) StatusQuery(db *sql.DB) (*sql.Rows, error) {
+	return db.Query("SELECT version_id, is_applied, tstamp from goose_db_version ORDER BY id DESC;")
+}
@@ -0,0 +1,39 @@
+package goose
+
+import (
+	"strings"
+	"testing"
+)
+
+// Each dialect's CREATE TABLE statement must express the "default to the
+// current time" column default in that engine's own syntax; a copy-pasted
+// statement that compiles for one engine but isn't valid SQL for another
+// defeats the point of having a SqlDialect per engine.
+func TestDialectCreateVersionTableSQLDiffers(t *testing.T) {
+	dialects := map[string]SqlDialect{
+		"postgres": PostgresDialect{},
+		"mysql":    MySqlDialect{},
+		"sqlite3":  Sqlite3Dialect{},
+	}
+
+	seen := make(map[string]string)
+	for name, d := range dialects {
+		sql := d.CreateVersionTableSQL()
+		if other, ok := seen[sql]; ok {
+			t.Errorf("%s and %s produce identical CreateVersionTableSQL, but their DDL dialects differ: %s", name, other, sql)
+		}
+		seen[sql] = name
+	}
+
+	if got := (MySqlDialect{}).CreateVersionTableSQL(); !strings.Contains(got, "CURRENT_TIMESTAMP") {
+		t.Errorf("MySqlDialect.CreateVersionTableSQL() = %q, want a CURRENT_TIMESTAMP default (bare now() is not valid MySQL)", got)
+	}
+
+	if got := (Sqlite3Dialect{}).CreateVersionTableSQL(); !strings.Contains(got, "datetime('now')") {
+		t.Errorf("Sqlite3Dialect.CreateVersionTableSQL() = %q, want a datetime('now') default", got)
+	}
+
+	if got := (PostgresDialect{}).CreateVersionTableSQL(); !strings.Contains(got, "now()") {
+		t.Errorf("PostgresDialect.CreateVersionTableSQL() = %q, want a now() default", got)
+	}
+}
@@ -0,0 +1,12 @@
+package goose
+
+import "database/sql"
+
+// Executor is satisfied by both *sql.DB and *sql.Tx, so migration
+// bookkeeping can run against whichever one a given migration step
+// actually uses.
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,55 @@
+package goose
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+)
+
+// GoMigration is the pair of functions a .go migration file registers for
+// a given version. Both run inside the same transaction as the version
+// bookkeeping insert, so user code participates in the automatic
+// rollback-on-failure behavior.
+type GoMigration struct {
+	Up   func(tx *sql.Tx) error
+	Down func(tx *sql.Tx) error
+}
+
+// goMigrations holds the Go migrations registered via AddMigration,
+// keyed by the migration's file name within the migrations FS, as
+// collectMigrations records it in Migration.Source.
+var goMigrations = map[string]GoMigration{}
+
+// AddMigration registers the Up/Down functions for a .go migration file.
+// Generated migration files (see the `create` subcommand) call this from
+// an init() function.
+func AddMigration(source string, up, down func(tx *sql.Tx) error) {
+	goMigrations[source] = GoMigration{Up: up, Down: down}
+}
+
+// runGoMigration runs the registered Up or Down function for filepath
+// inside tx, and returns 1 on success to mirror the statement count
+// reported for .sql migrations. If no function was registered for that
+// direction, nothing runs and it returns 0 with no error.
+func runGoMigration(tx *sql.Tx, fsys fs.FS, filepath string, v int64, direction bool) (int, error) {
+
+	m, ok := goMigrations[filepath]
+	if !ok {
+		return 0, fmt.Errorf("no Go migration registered for %s; did it call goose.AddMigration in init()?", filepath)
+	}
+
+	fn := m.Down
+	if direction {
+		fn = m.Up
+	}
+
+	if fn == nil {
+		return 0, nil
+	}
+
+	if err := fn(tx); err != nil {
+		return 0, err
+	}
+
+	return 1, nil
+}
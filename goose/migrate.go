@@ -0,0 +1,277 @@
+// Package goose is an embeddable migration library: applications can
+// //go:embed their migrations into the binary and call goose.Up at
+// process startup instead of shelling out to the goose CLI.
+package goose
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Migration struct {
+	Next     int64  // next version, or -1 if none
+	Previous int64  // previous version, -1 if none
+	Source   string // .go or .sql script, relative to the migrations FS
+}
+
+type MigrationMap struct {
+	Versions   []int64             // sorted slice of version keys
+	Migrations map[int64]Migration // sources (.sql or .go) keyed by version
+	Direction  bool                // sort direction: true -> Up, false -> Down
+}
+
+// Up applies every pending migration found in fsys, in order, up to and
+// including target. Pass target -1 to migrate to the most recent version
+// available.
+func Up(db *sql.DB, fsys fs.FS, dialect SqlDialect, target int64) error {
+	return runMigrations(db, fsys, dialect, target)
+}
+
+// Down rolls back every applied migration found in fsys, in order, down
+// to and including target. Pass target -1 (or 0) to roll all the way
+// back. Unlike Up, a negative target here can never mean "the most
+// recent version available" — there's no such thing as rolling back to
+// a version that hasn't even been applied yet.
+func Down(db *sql.DB, fsys fs.FS, dialect SqlDialect, target int64) error {
+	if target < 0 {
+		target = 0
+	}
+	return runMigrations(db, fsys, dialect, target)
+}
+
+func runMigrations(db *sql.DB, fsys fs.FS, dialect SqlDialect, target int64) error {
+
+	current, err := ensureDBVersion(db, dialect)
+	if err != nil {
+		return fmt.Errorf("couldn't get/set DB version: %v", err)
+	}
+
+	mm, err := collectMigrations(fsys, current, target)
+	if err != nil {
+		return err
+	}
+
+	if len(mm.Versions) == 0 {
+		return nil
+	}
+
+	for _, v := range mm.Versions {
+
+		filepath := mm.Migrations[v].Source
+
+		numStatements, e := runOneMigration(db, dialect, fsys, filepath, v, mm.Direction)
+		if e != nil {
+			return fmt.Errorf("FAIL %v, quitting migration", e)
+		}
+
+		fmt.Printf("OK   %s (%d statements)\n", path.Base(filepath), numStatements)
+	}
+
+	return nil
+}
+
+// runOneMigration runs a single migration and records its version bump in
+// the same database transaction, so a failure rolls back both the schema
+// change and the version bookkeeping atomically.
+//
+// A .sql migration that contains a "-- +goose NO TRANSACTION" marker line
+// opts out of this and runs directly against db instead, for statements
+// such as CREATE INDEX CONCURRENTLY that Postgres refuses to run inside a
+// transaction block. In that case the version bump is a separate,
+// non-atomic statement.
+func runOneMigration(db *sql.DB, dialect SqlDialect, fsys fs.FS, filepath string, v int64, direction bool) (int, error) {
+
+	if path.Ext(filepath) == ".sql" {
+		noTx, err := sqlHasNoTransactionMarker(fsys, filepath)
+		if err != nil {
+			return 0, err
+		}
+		if noTx {
+			numStatements, err := runSQLMigration(db, fsys, filepath, v, direction)
+			if err != nil {
+				return 0, err
+			}
+			return numStatements, insertVersion(db, dialect, v, direction)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var numStatements int
+
+	switch path.Ext(filepath) {
+	case ".go":
+		numStatements, err = runGoMigration(tx, fsys, filepath, v, direction)
+	case ".sql":
+		numStatements, err = runSQLMigration(tx, fsys, filepath, v, direction)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err = insertVersion(tx, dialect, v, direction); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return numStatements, tx.Commit()
+}
+
+// collect all the valid looking migration scripts in fsys, and key them
+// by version.
+func collectMigrations(fsys fs.FS, current, target int64) (mm *MigrationMap, err error) {
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	mm = &MigrationMap{
+		Migrations: make(map[int64]Migration),
+	}
+
+	// if target is the default -1,
+	// we need to find the most recent possible version to target
+	if target < 0 {
+		target = mostRecentVersionAvailable(entries)
+	}
+
+	// extract the numeric component of each migration,
+	// filter out any uninteresting files,
+	// and ensure we only have one file per migration version.
+	for _, entry := range entries {
+
+		name := entry.Name()
+
+		if ext := path.Ext(name); ext != ".go" && ext != ".sql" {
+			continue
+		}
+
+		v, e := numericComponent(name)
+		if e != nil {
+			continue
+		}
+
+		if _, ok := mm.Migrations[v]; ok {
+			return nil, fmt.Errorf("more than one file specifies the migration for version %d", v)
+		}
+
+		if versionFilter(v, current, target) {
+			mm.Append(v, name)
+		}
+	}
+
+	if len(mm.Versions) > 0 {
+		mm.Sort(current < target)
+	}
+
+	return mm, nil
+}
+
+// helper to identify the most recent possible version
+// within a folder of migration scripts
+func mostRecentVersionAvailable(entries []fs.DirEntry) int64 {
+
+	var mostRecent int64 = -1
+
+	for _, entry := range entries {
+
+		name := entry.Name()
+
+		if ext := path.Ext(name); ext != ".go" && ext != ".sql" {
+			continue
+		}
+
+		v, e := numericComponent(name)
+		if e != nil {
+			continue
+		}
+
+		if v > mostRecent {
+			mostRecent = v
+		}
+	}
+
+	return mostRecent
+}
+
+func versionFilter(v, current, target int64) bool {
+
+	// special case - default target value
+	if target < 0 {
+		return v > current
+	}
+
+	if target > current {
+		return v > current && v <= target
+	}
+
+	if target < current {
+		return v <= current && v >= target
+	}
+
+	return false
+}
+
+func (m *MigrationMap) Append(v int64, source string) {
+	m.Versions = append(m.Versions, v)
+	m.Migrations[v] = Migration{
+		Next:     -1,
+		Previous: -1,
+		Source:   source,
+	}
+}
+
+func (m *MigrationMap) Sort(direction bool) {
+	sort.Slice(m.Versions, func(i, j int) bool { return m.Versions[i] < m.Versions[j] })
+
+	// set direction, and reverse order if need be
+	m.Direction = direction
+	if m.Direction == false {
+		for i, j := 0, len(m.Versions)-1; i < j; i, j = i+1, j-1 {
+			m.Versions[i], m.Versions[j] = m.Versions[j], m.Versions[i]
+		}
+	}
+
+	// now that we're sorted in the appropriate direction,
+	// populate next and previous for each migration
+	//
+	// work around http://code.google.com/p/go/issues/detail?id=3117
+	var previousV int64 = -1
+	for _, v := range m.Versions {
+		cur := m.Migrations[v]
+		cur.Previous = previousV
+
+		// if a migration exists at prev, its next is now v
+		if prev, ok := m.Migrations[previousV]; ok {
+			prev.Next = v
+			m.Migrations[previousV] = prev
+		}
+
+		previousV = v
+	}
+}
+
+// look for migration scripts with names in the form:
+//  XXX_descriptivename.ext
+// where XXX is either a monotonic integer id or a 14-digit
+// YYYYMMDDHHMMSS timestamp (as produced by the create subcommand), and
+// ext specifies the type of migration.
+func numericComponent(name string) (int64, error) {
+	idx := strings.Index(name, "_")
+	if idx < 0 {
+		return 0, errors.New("no separator found")
+	}
+	return strconv.ParseInt(name[:idx], 10, 64)
+}
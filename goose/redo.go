@@ -0,0 +1,52 @@
+package goose
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Redo runs the most recently applied migration down and then back up
+// again, which is handy while iterating on a single migration during
+// development.
+func Redo(db *sql.DB, fsys fs.FS, dialect SqlDialect) error {
+
+	current, err := ensureDBVersion(db, dialect)
+	if err != nil {
+		return fmt.Errorf("couldn't get/set DB version: %v", err)
+	}
+	if current == 0 {
+		return errors.New("goose: no migrations have been applied, nothing to redo")
+	}
+
+	// current=0, target=-1 is the dedicated "give me every migration
+	// source on disk" call, matching the one used by Status — we only
+	// want the file for `current` here, not anything versionFilter would
+	// otherwise include or exclude based on a real range.
+	mm, err := collectMigrations(fsys, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	mig, ok := mm.Migrations[current]
+	if !ok {
+		return fmt.Errorf("goose: no migration source found for current version %d", current)
+	}
+
+	if _, err := runOneMigration(db, dialect, fsys, mig.Source, current, false); err != nil {
+		return fmt.Errorf("FAIL %v, quitting redo (down step)", err)
+	}
+
+	if _, err := runOneMigration(db, dialect, fsys, mig.Source, current, true); err != nil {
+		return fmt.Errorf("FAIL %v, quitting redo (up step)", err)
+	}
+
+	return nil
+}
+
+// Reset rolls every applied migration found in fsys back down to version
+// 0, reusing the same transactional execution path as Up/Down.
+func Reset(db *sql.DB, fsys fs.FS, dialect SqlDialect) error {
+	return Down(db, fsys, dialect, 0)
+}
@@ -0,0 +1,104 @@
+package goose
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+const (
+	sqlUpMarker   = "-- +goose Up"
+	sqlDownMarker = "-- +goose Down"
+	noTxMarker    = "-- +goose NO TRANSACTION"
+)
+
+// runSQLMigration executes the Up (or Down) section of a .sql migration
+// file against e, which is either the *sql.DB or an in-flight *sql.Tx,
+// and returns the number of statements it ran.
+func runSQLMigration(e Executor, fsys fs.FS, filepath string, v int64, direction bool) (int, error) {
+
+	statements, err := sqlStatements(fsys, filepath, direction)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, stmt := range statements {
+		if _, err := e.Exec(stmt); err != nil {
+			return 0, fmt.Errorf("%s: %v", filepath, err)
+		}
+	}
+
+	return len(statements), nil
+}
+
+// sqlStatements extracts the semicolon-separated statements under the
+// "-- +goose Up" or "-- +goose Down" marker, depending on direction.
+func sqlStatements(fsys fs.FS, filepath string, direction bool) ([]string, error) {
+
+	f, err := fsys.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	marker := sqlDownMarker
+	if direction {
+		marker = sqlUpMarker
+	}
+
+	var buf strings.Builder
+	inSection := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == sqlUpMarker || trimmed == sqlDownMarker {
+			inSection = trimmed == marker
+			continue
+		}
+
+		if inSection {
+			buf.WriteString(line)
+			buf.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	for _, stmt := range strings.Split(buf.String(), ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+
+	return statements, nil
+}
+
+// sqlHasNoTransactionMarker reports whether a .sql migration opts out of
+// running inside a transaction via a "-- +goose NO TRANSACTION" comment
+// line, for statements (e.g. CREATE INDEX CONCURRENTLY) that can't run in
+// one. The marker is recognised on any line of the file, not only before
+// the Up/Down sections.
+func sqlHasNoTransactionMarker(fsys fs.FS, filepath string) (bool, error) {
+
+	f, err := fsys.Open(filepath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == noTxMarker {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
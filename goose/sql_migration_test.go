@@ -0,0 +1,55 @@
+package goose
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSqlStatements(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_create_users.sql": &fstest.MapFile{Data: []byte(`-- +goose Up
+CREATE TABLE users (id int);
+ALTER TABLE users ADD COLUMN name text;
+
+-- +goose Down
+DROP TABLE users;
+`)},
+	}
+
+	up, err := sqlStatements(fsys, "001_create_users.sql", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUp := []string{"CREATE TABLE users (id int)", "ALTER TABLE users ADD COLUMN name text"}
+	if !reflect.DeepEqual(up, wantUp) {
+		t.Errorf("up statements = %v, want %v", up, wantUp)
+	}
+
+	down, err := sqlStatements(fsys, "001_create_users.sql", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDown := []string{"DROP TABLE users"}
+	if !reflect.DeepEqual(down, wantDown) {
+		t.Errorf("down statements = %v, want %v", down, wantDown)
+	}
+}
+
+func TestSqlHasNoTransactionMarker(t *testing.T) {
+	cases := map[string]bool{
+		"-- +goose Up\nCREATE INDEX CONCURRENTLY foo ON bar (baz);\n":                                  false,
+		"-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY foo ON bar (baz);\n":         true,
+	}
+
+	for contents, want := range cases {
+		fsys := fstest.MapFS{"001_x.sql": &fstest.MapFile{Data: []byte(contents)}}
+		got, err := sqlHasNoTransactionMarker(fsys, "001_x.sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("sqlHasNoTransactionMarker(%q) = %v, want %v", contents, got, want)
+		}
+	}
+}
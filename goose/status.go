@@ -0,0 +1,38 @@
+package goose
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+)
+
+// Status walks fsys and prints, for each migration, whether it is Pending
+// or Applied (with the timestamp of the most recent up migration), based
+// on the append-only goose_db_version log.
+func Status(db *sql.DB, fsys fs.FS, dialect SqlDialect) error {
+
+	applied, err := dbMigrationsStatus(db, dialect)
+	if err != nil {
+		return fmt.Errorf("couldn't get migration status: %v", err)
+	}
+
+	// current=0, target=-1 pulls in every migration on disk regardless
+	// of what's applied, so we can report status for the full set.
+	mm, err := collectMigrations(fsys, 0, -1)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("    Applied At                  Migration")
+	fmt.Println("    =======================================")
+	for _, v := range mm.Versions {
+		name := mm.Migrations[v].Source
+		if rec, ok := applied[v]; ok {
+			fmt.Printf("    %-24s -- %v\n", rec.TStamp.Format("2006-01-02 15:04:05"), name)
+		} else {
+			fmt.Printf("    %-24s -- %v\n", "Pending", name)
+		}
+	}
+
+	return nil
+}
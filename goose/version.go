@@ -0,0 +1,150 @@
+package goose
+
+import (
+	"database/sql"
+	"time"
+)
+
+// MigrationRecord is a single row of the append-only goose_db_version log.
+// The current version is the most recent row with IsApplied=true that has
+// no later IsApplied=false row for the same VersionId.
+type MigrationRecord struct {
+	VersionId int64
+	IsApplied bool
+	TStamp    time.Time
+}
+
+// ensureDBVersion retrieves the current version for this DB, creating and
+// initializing the goose_db_version table if it doesn't exist yet.
+//
+// goose_db_version is an append-only log: every up or down migration
+// inserts a new row rather than mutating an existing one, so the table
+// doubles as a history of what has been applied and rolled back.
+func ensureDBVersion(db *sql.DB, d SqlDialect) (int64, error) {
+
+	rows, err := d.DbVersionQuery(db)
+	if err != nil {
+		return createVersionTable(db, d)
+	}
+	defer rows.Close()
+
+	var log []MigrationRecord
+	for rows.Next() {
+		var row MigrationRecord
+		if err = rows.Scan(&row.VersionId, &row.IsApplied); err != nil {
+			return 0, err
+		}
+		log = append(log, row)
+	}
+
+	return currentVersionFromLog(log), nil
+}
+
+// currentVersionFromLog reduces the append-only goose_db_version log,
+// ordered most recent row first, down to a single current version: the
+// most recent version for a given id wins, so once we've seen a
+// version_id we ignore any older rows for that same id.
+func currentVersionFromLog(log []MigrationRecord) int64 {
+
+	toSkip := make([]int64, 0)
+
+	for _, row := range log {
+
+		skip := false
+		for _, v := range toSkip {
+			if v == row.VersionId {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		if row.IsApplied {
+			return row.VersionId
+		}
+
+		toSkip = append(toSkip, row.VersionId)
+	}
+
+	return 0
+}
+
+// createVersionTable creates the goose_db_version table and inserts the
+// initial "applied" record for version 0. It's called the first time
+// goose runs against a database that doesn't have the table yet.
+func createVersionTable(db *sql.DB, d SqlDialect) (int64, error) {
+
+	txn, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := txn.Exec(d.CreateVersionTableSQL()); err != nil {
+		txn.Rollback()
+		return 0, err
+	}
+
+	if _, err := txn.Exec(d.InsertVersionSQL(), 0, true); err != nil {
+		txn.Rollback()
+		return 0, err
+	}
+
+	return 0, txn.Commit()
+}
+
+// insertVersion appends a row to goose_db_version recording that version
+// v has just been applied (up) or rolled back (down). e is either the
+// *sql.DB or the in-flight *sql.Tx the migration itself ran against, so
+// the version bump can participate in the same transaction.
+func insertVersion(e Executor, d SqlDialect, v int64, applied bool) error {
+	_, err := e.Exec(d.InsertVersionSQL(), v, applied)
+	return err
+}
+
+// dbMigrationsStatus loads the append-only goose_db_version log and hands
+// it to appliedVersionsFromLog to compute the current status of each
+// version.
+func dbMigrationsStatus(db *sql.DB, d SqlDialect) (map[int64]MigrationRecord, error) {
+
+	rows, err := d.StatusQuery(db)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var log []MigrationRecord
+	for rows.Next() {
+		var row MigrationRecord
+		if err := rows.Scan(&row.VersionId, &row.IsApplied, &row.TStamp); err != nil {
+			return nil, err
+		}
+		log = append(log, row)
+	}
+
+	return appliedVersionsFromLog(log), nil
+}
+
+// appliedVersionsFromLog reduces the append-only goose_db_version log,
+// ordered most recent row first, down to the current status of each
+// version: the most recent row for a given version_id wins, and only
+// versions whose most recent row is is_applied=true are reported.
+func appliedVersionsFromLog(log []MigrationRecord) map[int64]MigrationRecord {
+
+	seen := make(map[int64]bool)
+	applied := make(map[int64]MigrationRecord)
+
+	for _, row := range log {
+		if seen[row.VersionId] {
+			continue
+		}
+		seen[row.VersionId] = true
+
+		if row.IsApplied {
+			applied[row.VersionId] = row
+		}
+	}
+
+	return applied
+}
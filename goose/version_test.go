@@ -0,0 +1,70 @@
+package goose
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// log entries are passed in the same order ensureDBVersion/dbMigrationsStatus
+// see them: most recent row (highest id) first.
+func TestCurrentVersionFromLog(t *testing.T) {
+	cases := []struct {
+		name string
+		log  []MigrationRecord
+		want int64
+	}{
+		{"empty log", nil, 0},
+		{
+			"single applied version",
+			[]MigrationRecord{{VersionId: 1, IsApplied: true}},
+			1,
+		},
+		{
+			"most recent row for a version wins: rolled back after being applied",
+			[]MigrationRecord{
+				{VersionId: 2, IsApplied: false}, // down
+				{VersionId: 2, IsApplied: true},  // up
+				{VersionId: 1, IsApplied: true},
+			},
+			1,
+		},
+		{
+			"re-applied after a rollback",
+			[]MigrationRecord{
+				{VersionId: 2, IsApplied: true},  // up again
+				{VersionId: 2, IsApplied: false}, // down
+				{VersionId: 2, IsApplied: true},  // up
+				{VersionId: 1, IsApplied: true},
+			},
+			2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := currentVersionFromLog(c.log); got != c.want {
+				t.Errorf("currentVersionFromLog(%+v) = %d, want %d", c.log, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAppliedVersionsFromLog(t *testing.T) {
+	applied := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	log := []MigrationRecord{
+		{VersionId: 2, IsApplied: false, TStamp: applied.Add(2 * time.Hour)}, // rolled back
+		{VersionId: 2, IsApplied: true, TStamp: applied.Add(time.Hour)},
+		{VersionId: 1, IsApplied: true, TStamp: applied},
+	}
+
+	got := appliedVersionsFromLog(log)
+	want := map[int64]MigrationRecord{
+		1: {VersionId: 1, IsApplied: true, TStamp: applied},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("appliedVersionsFromLog(%+v) = %+v, want %+v (version 2 was rolled back and must not be reported applied)", log, got, want)
+	}
+}